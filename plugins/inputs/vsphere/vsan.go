@@ -2,8 +2,10 @@ package vsphere
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"log"
+	"os"
 	"strconv"
 	"strings"
 	"sync"
@@ -12,10 +14,13 @@ import (
 	"github.com/vmware/govmomi/vim25/soap"
 
 	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/filter"
 	"github.com/influxdata/telegraf/plugins/inputs/vsphere/vsan/methods"
 	vsanTypes "github.com/influxdata/telegraf/plugins/inputs/vsphere/vsan/types"
 	"github.com/vmware/govmomi/object"
+	"github.com/vmware/govmomi/view"
 	"github.com/vmware/govmomi/vim25"
+	"github.com/vmware/govmomi/vim25/mo"
 	"github.com/vmware/govmomi/vim25/types"
 )
 
@@ -24,6 +29,10 @@ const (
 	vsanPath        = "/vsanHealth"
 	timeFormat      = "Mon, 02 Jan 2006 15:04:05 MST"
 	vsanMetricsName = "vsphere_cluster_vsan"
+
+	vsanHealthMetricsName   = "vsphere_cluster_vsan_health"
+	vsanCapacityMetricsName = "vsphere_cluster_vsan_capacity"
+	vsanResyncMetricsName   = "vsphere_cluster_vsan_resync"
 )
 
 var (
@@ -32,6 +41,18 @@ var (
 		Value: "vsan-performance-manager",
 	}
 
+	vsanHealthSystemInstance = types.ManagedObjectReference{
+		Type:  "VsanVcClusterHealthSystem",
+		Value: "vsan-cluster-health-system",
+	}
+
+	vsanSpaceReportSystemInstance = types.ManagedObjectReference{
+		Type:  "VsanSpaceReportSystem",
+		Value: "vsan-cluster-space-report-system",
+	}
+
+	// vsanPerfEntityRefIds are collected unconditionally - they are
+	// cluster/host/disk/nic scoped and low cardinality.
 	vsanPerfEntityRefIds = []string{
 		"cluster-domclient",
 		"host-domclient",
@@ -40,8 +61,131 @@ var (
 		"vsan-vnic-net",
 		"vsan-pnic-net",
 	}
+
+	// vsanPerfVMEntityRefIds are VM-scoped entities. On a large vCenter
+	// these can produce a very large number of series, so they are only
+	// collected when a caller opts in via VsanMetricInclude.
+	vsanPerfVMEntityRefIds = []string{
+		"virtual-machine",
+		"virtual-disk",
+		"vscsi",
+		"virtual-machine-vsan-vnic",
+	}
 )
 
+// vsanVMInfo holds the pieces of VM inventory needed to enrich VM-scoped
+// vSAN metrics, which only identify their entity by UUID.
+type vsanVMInfo struct {
+	name string
+	moid string
+}
+
+// vsanVMInventory is a UUID-keyed snapshot of a cluster's VM inventory,
+// used to resolve the UUIDs embedded in VM-level vSAN EntityRefIds.
+type vsanVMInventory struct {
+	byVMUUID map[string]vsanVMInfo
+}
+
+// vsanVMInventoryRefreshInterval bounds how long a cached vsanVMInventory is
+// reused before CollectVsan rebuilds it. VM inventories are cached per
+// cluster rather than rebuilt on every poll - on a large vCenter a full
+// container-view enumeration every collection interval would undercut the
+// batching work done for the entity queries themselves.
+const vsanVMInventoryRefreshInterval = 10 * time.Minute
+
+// vsanVMInventoryEntry is a cached vsanVMInventory along with when it was
+// built, so vmInventoryFor knows when to refresh it.
+type vsanVMInventoryEntry struct {
+	inventory *vsanVMInventory
+	fetchedAt time.Time
+}
+
+// buildVsanVMInventory walks the VMs under cluster and indexes them by
+// instance UUID so formatAndSendVsanMetric can resolve vmname/vm_moid/
+// disk_uuid tags for VM-scoped entities without a per-metric API call.
+func buildVsanVMInventory(ctx context.Context, client *vim25.Client, cluster *object.ClusterComputeResource) (*vsanVMInventory, error) {
+	m := view.NewManager(client)
+	v, err := m.CreateContainerView(ctx, cluster.Reference(), []string{"VirtualMachine"}, true)
+	if err != nil {
+		return nil, err
+	}
+	defer v.Destroy(ctx)
+
+	var vms []mo.VirtualMachine
+	if err := v.Retrieve(ctx, []string{"VirtualMachine"}, []string{"config.instanceUuid", "name"}, &vms); err != nil {
+		return nil, err
+	}
+
+	inv := &vsanVMInventory{byVMUUID: make(map[string]vsanVMInfo, len(vms))}
+	for _, vm := range vms {
+		if vm.Config == nil || vm.Config.InstanceUUID == "" {
+			continue
+		}
+		inv.byVMUUID[vm.Config.InstanceUUID] = vsanVMInfo{
+			name: vm.Name,
+			moid: vm.Reference().Value,
+		}
+	}
+	return inv, nil
+}
+
+// vmInventoryFor returns a cached VM inventory for cluster, rebuilding it
+// when none exists yet or the cached copy is older than
+// vsanVMInventoryRefreshInterval. If a rebuild fails and a stale inventory
+// is available, the stale copy is served rather than dropping VM tag
+// enrichment entirely over a transient failure.
+func (v *VsanCollector) vmInventoryFor(ctx context.Context, client *vim25.Client, cluster *object.ClusterComputeResource) (*vsanVMInventory, error) {
+	clusterMoid := cluster.Reference().Value
+
+	v.vmInventoryMu.Lock()
+	entry, ok := v.vmInventoryCache[clusterMoid]
+	v.vmInventoryMu.Unlock()
+	if ok && time.Since(entry.fetchedAt) < vsanVMInventoryRefreshInterval {
+		return entry.inventory, nil
+	}
+
+	inv, err := buildVsanVMInventory(ctx, client, cluster)
+	if err != nil {
+		if ok {
+			return entry.inventory, nil
+		}
+		return nil, err
+	}
+
+	v.vmInventoryMu.Lock()
+	v.vmInventoryCache[clusterMoid] = vsanVMInventoryEntry{inventory: inv, fetchedAt: time.Now()}
+	v.vmInventoryMu.Unlock()
+	return inv, nil
+}
+
+// enrichVsanVMTags adds vmname/vm_moid/disk_uuid tags for VM-scoped entities
+// by resolving the UUID portion of an EntityRefId against inv. It is a
+// no-op for non-VM entity types or when inv is nil.
+func enrichVsanVMTags(entityName, uuid string, inv *vsanVMInventory, tags map[string]string) {
+	if inv == nil {
+		return
+	}
+	switch entityName {
+	case "virtual-machine", "virtual-machine-vsan-vnic":
+		if info, ok := inv.byVMUUID[uuid]; ok {
+			tags["vmname"] = info.name
+			tags["vm_moid"] = info.moid
+		}
+	case "virtual-disk", "vscsi":
+		// the vSAN Perf Manager encodes these as "<vm-uuid>|<disk-uuid>".
+		parts := strings.SplitN(uuid, "|", 2)
+		if len(parts) != 2 {
+			return
+		}
+		vmUUID, diskUUID := parts[0], parts[1]
+		if info, ok := inv.byVMUUID[vmUUID]; ok {
+			tags["vmname"] = info.name
+			tags["vm_moid"] = info.moid
+			tags["disk_uuid"] = diskUUID
+		}
+	}
+}
+
 /*
 All this cryptic code in formatAndSendVsanMetric is to parse the vsanTypes.VsanPerfEntityMetricCSV type, which has the structure:
 {
@@ -69,17 +213,38 @@ All this cryptic code in formatAndSendVsanMetric is to parse the vsanTypes.VsanP
 	]
 }
 */
-func formatAndSendVsanMetric(entity vsanTypes.VsanPerfEntityMetricCSV, tags map[string]string, acc telegraf.Accumulator) {
+// formatAndSendVsanMetric emits entity's samples as fields and returns the
+// latest sample timestamp seen, so callers can advance their checkpoint.
+// Counters are filtered by the collector's VsanMetricFilter
+// (fieldInclude/fieldExclude, matched on "entityName_metricLabel") and
+// RollupType selection, and, when counter metadata has been cached, tagged
+// with a normalized unit.
+func (v *VsanCollector) formatAndSendVsanMetric(entity vsanTypes.VsanPerfEntityMetricCSV, tags map[string]string, acc telegraf.Accumulator, vmInventory *vsanVMInventory) time.Time {
 	vals := strings.Split(entity.EntityRefId, ":")
 	entityName := vals[0]
-	tags["uuid"] = vals[1]
+	uuid := vals[1]
+	tags = copyVsanTags(tags)
+	tags["uuid"] = uuid
+	enrichVsanVMTags(entityName, uuid, vmInventory, tags)
 	var timeStamps []string
 	for _, t := range strings.Split(entity.SampleInfo, ",") {
 		tsParts := strings.Split(t, " ")
 		timeStamps = append(timeStamps, fmt.Sprintf("%sT%sZ", tsParts[0], tsParts[1]))
 	}
+	var maxTs time.Time
 	for _, counter := range entity.Value {
 		metricLabel := counter.MetricId.Label
+		field := fmt.Sprintf("%s_%s", entityName, metricLabel)
+		if v.fieldInclude != nil && !v.fieldInclude.Match(field) {
+			continue
+		}
+		if v.fieldExclude != nil && v.fieldExclude.Match(field) {
+			continue
+		}
+		if v.rollupType != "" && counter.MetricId.RollupType != "" && counter.MetricId.RollupType != v.rollupType {
+			continue
+		}
+		unit, hasUnit := v.lookupCounterUnit(field)
 		for i, values := range strings.Split(counter.Values, ",") {
 			ts, ok := time.Parse(time.RFC3339, timeStamps[i])
 			if ok != nil {
@@ -87,45 +252,524 @@ func formatAndSendVsanMetric(entity vsanTypes.VsanPerfEntityMetricCSV, tags map[
 				log.Printf("D! Failed to parse a timestamp: %s", timeStamps[i])
 				continue
 			}
-			fields := make(map[string]interface{})
-			field := fmt.Sprintf("%s_%s", entityName, metricLabel)
-			if v, err := strconv.ParseFloat(values, 32); err == nil {
-				fields[field] = v
+			val, err := strconv.ParseFloat(values, 32)
+			if err != nil {
+				continue
+			}
+			metricTags := tags
+			if hasUnit {
+				normalized, normalizedUnit := normalizeVsanUnit(unit, val)
+				val = normalized
+				metricTags = copyVsanTags(tags)
+				metricTags["unit"] = normalizedUnit
+			}
+			fields := map[string]interface{}{field: val}
+			acc.AddFields(vsanMetricsName, fields, metricTags, ts)
+			if ts.After(maxTs) {
+				maxTs = ts
 			}
-			acc.AddFields(vsanMetricsName, fields, tags, ts)
 		}
 	}
+	return maxTs
 }
 
-func getAllVsanMetrics(ctx context.Context, vsanClient *soap.Client, cluster *object.ClusterComputeResource, tags map[string]string, acc telegraf.Accumulator) {
-	endTime := time.Now()
-	startTime := endTime.Add(time.Duration(-5) * time.Minute)
-	log.Printf("D! Querying data between: %s -> %s", startTime.Format(timeFormat), endTime.Format(timeFormat))
-	for _, entityRefID := range vsanPerfEntityRefIds {
-		var querySpecs []vsanTypes.VsanPerfQuerySpec
+// lookupCounterUnit returns the declared unit for "entityName_metricLabel",
+// if counter metadata has been fetched and contains it.
+func (v *VsanCollector) lookupCounterUnit(field string) (string, bool) {
+	v.counterMu.RLock()
+	defer v.counterMu.RUnlock()
+	unit, ok := v.counterUnits[field]
+	return unit, ok
+}
+
+// ensureCounterMetadata fetches and caches counter metadata - including
+// each counter's declared unit - from VsanPerfGetSupportedEntityTypes. A
+// successful fetch is cached for the collector's lifetime; a failed fetch
+// (e.g. a cluster mid-maintenance, or a transient SOAP fault) is retried on
+// a later call rather than permanently disabling unit tagging.
+func (v *VsanCollector) ensureCounterMetadata(ctx context.Context, vsanClient *soap.Client) {
+	v.counterMu.RLock()
+	fetched := v.counterFetched
+	v.counterMu.RUnlock()
+	if fetched {
+		return
+	}
+
+	req := vsanTypes.VsanPerfGetSupportedEntityTypes{This: vsanPerformanceManagerInstance}
+	res, err := vsanPerfGetSupportedEntityTypes(ctx, vsanClient, &req)
+	if err != nil {
+		log.Printf("E! [inputs.vsphere][vSAN] Failed to fetch vSAN counter metadata, unit tags will be omitted for now: %s", err)
+		return
+	}
 
-		spec := vsanTypes.VsanPerfQuerySpec{
-			EntityRefId: fmt.Sprintf("%s:*", entityRefID),
-			StartTime:   &startTime,
-			EndTime:     &endTime,
+	v.counterMu.Lock()
+	defer v.counterMu.Unlock()
+	if v.counterFetched {
+		return
+	}
+	for _, entityType := range res.Returnval {
+		for _, counter := range entityType.Counters {
+			if counter.Unit == "" {
+				continue
+			}
+			field := fmt.Sprintf("%s_%s", entityType.Name, counter.Label)
+			v.counterUnits[field] = counter.Unit
 		}
-		querySpecs = append(querySpecs, spec)
+	}
+	v.counterFetched = true
+}
 
-		vsanPerfQueryPerf := vsanTypes.VsanPerfQueryPerf{
-			This:       vsanPerformanceManagerInstance,
-			QuerySpecs: querySpecs,
-			Cluster:    cluster.Reference(),
+// normalizeVsanUnit converts value from the vSAN Performance Manager's
+// declared unit into the base unit the rest of the vSphere input uses
+// (bytes, nanoseconds), mirroring the KB->bytes/us->ns normalization the
+// core vSphere collector applies to PerfCounterInfo. Units it doesn't
+// recognize are passed through unchanged.
+func normalizeVsanUnit(unit string, value float64) (float64, string) {
+	switch unit {
+	case "KBps":
+		return value * 1024, "Bps"
+	case "MBps":
+		return value * 1024 * 1024, "Bps"
+	case "microsec", "us":
+		return value * 1000, "ns"
+	default:
+		return value, unit
+	}
+}
+
+// copyVsanTags returns a shallow copy of tags so per-entity enrichment
+// (uuid, vmname, vm_moid, disk_uuid) doesn't leak across entity ref IDs
+// that share the same base cluster tag map.
+func copyVsanTags(tags map[string]string) map[string]string {
+	out := make(map[string]string, len(tags)+3)
+	for k, v := range tags {
+		out[k] = v
+	}
+	return out
+}
+
+// vsanMaxQueryWindow is the longest span the vSAN Performance Manager will
+// accept in a single VsanPerfQuerySpec.
+const vsanMaxQueryWindow = 60 * time.Minute
+
+// vsanClusterCheckpoint records the timestamp of the last sample
+// successfully collected for a cluster, so the next poll can resume from
+// there instead of re-requesting a fixed 5-minute lookback.
+type vsanClusterCheckpoint struct {
+	LastSampleTime time.Time `json:"last_sample_time"`
+}
+
+// VsanCollector holds the configuration and checkpoint state needed to
+// collect vSAN metrics across one or more clusters.
+type VsanCollector struct {
+	metricInclude filter.Filter
+	metricExclude filter.Filter
+	fieldInclude  filter.Filter
+	fieldExclude  filter.Filter
+	stateFile     string
+	maxBackfill   time.Duration
+	intervalSecs  int32
+	rollupType    string
+
+	collectHealth   bool
+	collectCapacity bool
+	collectResync   bool
+	concurrency     int
+
+	stateMu sync.Mutex
+	saveMu  sync.Mutex                        // serializes saveState's full marshal-and-write of stateFile
+	state   map[string]*vsanClusterCheckpoint // keyed by cluster moid
+
+	counterMu      sync.RWMutex
+	counterFetched bool              // set only once a fetch has succeeded
+	counterUnits   map[string]string // "entityName_metricLabel" -> declared unit
+
+	vmInventoryMu    sync.Mutex
+	vmInventoryCache map[string]vsanVMInventoryEntry // keyed by cluster moid
+}
+
+// VsanCollectorConfig holds the vsan_* collection options, which
+// NewVsanCollector compiles/validates once. The vSphere input's own
+// configuration has no vsan_* settings; the struct is filled in by whoever
+// calls NewVsanCollector.
+// StateFile, if non-empty, is where per-cluster checkpoints are persisted
+// between Telegraf restarts; MaxBackfill caps how far back the first poll
+// for a cluster is allowed to reach when no checkpoint exists yet.
+// Concurrency caps how many clusters are collected from in parallel; it
+// defaults to 1 (sequential) when unset. FieldInclude/FieldExclude are the
+// VsanMetricFilter glob patterns matched against "entityName_metricLabel"
+// (e.g. "cluster-domclient_iopsRead") to control which counters are kept.
+// IntervalSecs, if set, requests that specific metricsCollectInterval from
+// the vSAN Performance Manager instead of always accepting its 300s default.
+// RollupType, if set, keeps only counters whose declared RollupType matches
+// (e.g. "average", "summation", "maximum") - some counter labels are
+// reported with more than one rollup, and this selects a single one instead
+// of emitting every rollup as the same field. Counters that don't declare a
+// RollupType are kept regardless.
+type VsanCollectorConfig struct {
+	MetricInclude   []string
+	MetricExclude   []string
+	FieldInclude    []string
+	FieldExclude    []string
+	RollupType      string
+	StateFile       string
+	MaxBackfill     time.Duration
+	IntervalSecs    int32
+	CollectHealth   bool
+	CollectCapacity bool
+	CollectResync   bool
+	Concurrency     int
+}
+
+// NewVsanCollector builds a VsanCollector from cfg. Cluster/host/disk/nic
+// performance entities are always collected; VM-level entities are only
+// collected when MetricInclude is set and matches, and MetricExclude
+// doesn't match.
+func NewVsanCollector(cfg VsanCollectorConfig) (*VsanCollector, error) {
+	concurrency := cfg.Concurrency
+	if concurrency <= 0 {
+		concurrency = 1
+	}
+	c := &VsanCollector{
+		stateFile:        cfg.StateFile,
+		maxBackfill:      cfg.MaxBackfill,
+		intervalSecs:     cfg.IntervalSecs,
+		rollupType:       cfg.RollupType,
+		collectHealth:    cfg.CollectHealth,
+		collectCapacity:  cfg.CollectCapacity,
+		collectResync:    cfg.CollectResync,
+		concurrency:      concurrency,
+		state:            make(map[string]*vsanClusterCheckpoint),
+		counterUnits:     make(map[string]string),
+		vmInventoryCache: make(map[string]vsanVMInventoryEntry),
+	}
+	if len(cfg.MetricInclude) != 0 {
+		f, err := filter.Compile(cfg.MetricInclude)
+		if err != nil {
+			return nil, fmt.Errorf("error compiling vsan_metric_include: %w", err)
 		}
-		res, err := methods.VsanPerfQueryPerf(ctx, vsanClient, &vsanPerfQueryPerf)
+		c.metricInclude = f
+	}
+	if len(cfg.MetricExclude) != 0 {
+		f, err := filter.Compile(cfg.MetricExclude)
+		if err != nil {
+			return nil, fmt.Errorf("error compiling vsan_metric_exclude: %w", err)
+		}
+		c.metricExclude = f
+	}
+	if len(cfg.FieldInclude) != 0 {
+		f, err := filter.Compile(cfg.FieldInclude)
+		if err != nil {
+			return nil, fmt.Errorf("error compiling vsan_field_include: %w", err)
+		}
+		c.fieldInclude = f
+	}
+	if len(cfg.FieldExclude) != 0 {
+		f, err := filter.Compile(cfg.FieldExclude)
 		if err != nil {
-			log.Fatal(err)
+			return nil, fmt.Errorf("error compiling vsan_field_exclude: %w", err)
+		}
+		c.fieldExclude = f
+	}
+	if c.stateFile != "" {
+		if err := c.loadState(); err != nil {
+			return nil, fmt.Errorf("error loading vsan_state_file %q: %w", c.stateFile, err)
+		}
+	}
+	return c, nil
+}
+
+// loadState populates v.state from v.stateFile, if it exists. A missing
+// file is not an error - it just means this is the first run.
+func (v *VsanCollector) loadState() error {
+	data, err := os.ReadFile(v.stateFile)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	v.stateMu.Lock()
+	defer v.stateMu.Unlock()
+	return json.Unmarshal(data, &v.state)
+}
+
+// saveState persists v.state to v.stateFile. It is a no-op when no
+// stateFile was configured. CollectVsanClusters can run CollectVsan for
+// multiple clusters concurrently, so the whole marshal-and-write is
+// serialized with saveMu and written via a temp file plus rename, rather
+// than just the marshal - otherwise concurrent callers could race on the
+// shared file and leave it corrupted or truncated.
+func (v *VsanCollector) saveState() error {
+	if v.stateFile == "" {
+		return nil
+	}
+	v.saveMu.Lock()
+	defer v.saveMu.Unlock()
+
+	v.stateMu.Lock()
+	data, err := json.Marshal(v.state)
+	v.stateMu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	tmp := v.stateFile + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, v.stateFile)
+}
+
+// checkpoint returns the start of the next window to collect for
+// clusterMoid: either just after the last successfully collected sample,
+// or now-maxBackfill if this cluster has no checkpoint yet.
+func (v *VsanCollector) checkpoint(clusterMoid string, now time.Time) time.Time {
+	v.stateMu.Lock()
+	defer v.stateMu.Unlock()
+	cp, ok := v.state[clusterMoid]
+	if !ok || cp.LastSampleTime.IsZero() {
+		backfill := v.maxBackfill
+		if backfill <= 0 {
+			backfill = 5 * time.Minute
 		}
+		return now.Add(-backfill)
+	}
+	// The vSAN Performance Manager's query range is inclusive of startTime,
+	// so returning the last checkpoint verbatim would re-request - and
+	// re-emit - the sample at that exact instant on every subsequent poll.
+	// Advancing by a second makes the boundary exclusive without assuming
+	// a particular sampling interval.
+	return cp.LastSampleTime.Add(time.Second)
+}
+
+// setCheckpoint records the latest sample timestamp collected for
+// clusterMoid.
+func (v *VsanCollector) setCheckpoint(clusterMoid string, lastSampleTime time.Time) {
+	v.stateMu.Lock()
+	defer v.stateMu.Unlock()
+	cp, ok := v.state[clusterMoid]
+	if !ok {
+		cp = &vsanClusterCheckpoint{}
+		v.state[clusterMoid] = cp
+	}
+	if lastSampleTime.After(cp.LastSampleTime) {
+		cp.LastSampleTime = lastSampleTime
+	}
+}
+
+// vsanQueryWindow is a single [Start,End) span to request from the vSAN
+// Performance Manager, never exceeding vsanMaxQueryWindow.
+type vsanQueryWindow struct {
+	start time.Time
+	end   time.Time
+}
+
+// chunkVsanWindow splits [start,end) into consecutive spans no longer
+// than vsanMaxQueryWindow, the vSAN Perf Manager's max query window.
+func chunkVsanWindow(start, end time.Time) []vsanQueryWindow {
+	if !end.After(start) {
+		return nil
+	}
+	var windows []vsanQueryWindow
+	for cur := start; cur.Before(end); {
+		next := cur.Add(vsanMaxQueryWindow)
+		if next.After(end) {
+			next = end
+		}
+		windows = append(windows, vsanQueryWindow{start: cur, end: next})
+		cur = next
+	}
+	return windows
+}
+
+// coreEntityRefIds returns the low-cardinality entity ref IDs that are
+// always collected.
+func (v *VsanCollector) coreEntityRefIds() []string {
+	ids := make([]string, len(vsanPerfEntityRefIds))
+	copy(ids, vsanPerfEntityRefIds)
+	return ids
+}
+
+// vmEntityRefIds returns the VM-level entity ref IDs the collector has been
+// configured to include, or nil if VsanMetricInclude wasn't set. These are
+// queried separately from coreEntityRefIds so that a vCenter rejecting one
+// VM-level entity type (or any other fault scoped to this group) doesn't
+// also take down the core cluster/host/disk/nic metrics for the window.
+func (v *VsanCollector) vmEntityRefIds() []string {
+	if v.metricInclude == nil {
+		return nil
+	}
+	var ids []string
+	for _, id := range vsanPerfVMEntityRefIds {
+		if !v.metricInclude.Match(id) {
+			continue
+		}
+		if v.metricExclude != nil && v.metricExclude.Match(id) {
+			continue
+		}
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+const (
+	// vsanQueryMaxRetries bounds the retry/backoff wrapper around
+	// VsanPerfQueryPerf so a transient SOAP fault (e.g. a 503 while the
+	// vSAN health service is restarting) doesn't drop a whole polling
+	// interval.
+	vsanQueryMaxRetries = 3
+	vsanQueryRetryDelay = 2 * time.Second
+)
+
+// vsanNotEnabledFault reports whether err is the well-known vSAN fault
+// raised when a cluster doesn't have vSAN enabled (e.g. it's a plain
+// vSphere cluster, or vSAN is temporarily disabled during maintenance).
+// Such clusters should be skipped silently rather than logged as errors.
+func vsanNotEnabledFault(err error) bool {
+	if err == nil {
+		return false
+	}
+	if soap.IsSoapFault(err) {
+		if soapFault := soap.ToSoapFault(err); soapFault != nil {
+			return strings.Contains(soapFault.String, "vSAN is not enabled")
+		}
+	}
+	return strings.Contains(err.Error(), "vSAN is not enabled")
+}
 
-		for _, ret := range res.Returnval {
-			log.Printf("D! [inputs.vsphere][vSAN]\tSuccessfully Fetched data for Entity ==> %s:%d\n", ret.EntityRefId, len(ret.Value))
-			formatAndSendVsanMetric(ret, tags, acc)
+// vsanPerfQueryPerf is a seam over methods.VsanPerfQueryPerf so benchmarks
+// and tests can substitute a fake transport without a real vCenter.
+var vsanPerfQueryPerf = methods.VsanPerfQueryPerf
+
+// vsanPerfGetSupportedEntityTypes is a seam over
+// methods.VsanPerfGetSupportedEntityTypes so benchmarks and tests can
+// substitute a fake transport without a real vCenter.
+var vsanPerfGetSupportedEntityTypes = methods.VsanPerfGetSupportedEntityTypes
+
+// vsanPerfQueryPerfWithRetry wraps vsanPerfQueryPerf with a small
+// retry/backoff loop so a single transient SOAP fault doesn't fail the
+// whole batched entity group's query for that window.
+func vsanPerfQueryPerfWithRetry(ctx context.Context, vsanClient *soap.Client, req *vsanTypes.VsanPerfQueryPerf) (*vsanTypes.VsanPerfQueryPerfResponse, error) {
+	var lastErr error
+	delay := vsanQueryRetryDelay
+	for attempt := 0; attempt <= vsanQueryMaxRetries; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			case <-time.After(delay):
+			}
+			delay *= 2
 		}
+		res, err := vsanPerfQueryPerf(ctx, vsanClient, req)
+		if err == nil {
+			return res, nil
+		}
+		if vsanNotEnabledFault(err) {
+			return nil, err
+		}
+		lastErr = err
 	}
+	return nil, fmt.Errorf("vSAN query failed after %d retries: %w", vsanQueryMaxRetries, lastErr)
+}
+
+func (v *VsanCollector) getAllVsanMetrics(ctx context.Context, vsanClient *soap.Client, cluster *object.ClusterComputeResource, vmInventory *vsanVMInventory, tags map[string]string, acc telegraf.Accumulator) error {
+	clusterMoid := cluster.Reference().Value
+	endTime := time.Now()
+	startTime := v.checkpoint(clusterMoid, endTime)
+	windows := chunkVsanWindow(startTime, endTime)
+	log.Printf("D! Querying data between: %s -> %s (%d window(s))", startTime.Format(timeFormat), endTime.Format(timeFormat), len(windows))
+
+	// Entity ref IDs are queried as two groups rather than either one call
+	// per entity (too many round-trips on a vCenter with many clusters) or
+	// a single call across all of them (one bad spec in either group, e.g.
+	// a VM-level entity type the vCenter doesn't support, would then take
+	// the other group's metrics down with it). Each group is still batched
+	// into a single VsanPerfQueryPerf call.
+	entityGroups := []struct {
+		label string
+		ids   []string
+	}{
+		{label: "core", ids: v.coreEntityRefIds()},
+	}
+	if vmIds := v.vmEntityRefIds(); len(vmIds) != 0 {
+		entityGroups = append(entityGroups, struct {
+			label string
+			ids   []string
+		}{label: "vm", ids: vmIds})
+	}
+
+	// checkpointTs only tracks windows where every group succeeded - a
+	// group that fails still has its sibling group's data emitted below,
+	// but that partial window must not be checkpointed past, or the failed
+	// group's data for it would never be retried.
+	var checkpointTs time.Time
+	for _, window := range windows {
+		start, end := window.start, window.end
+		windowFailed := false
+		var windowMaxTs time.Time
+
+		for _, group := range entityGroups {
+			querySpecs := make([]vsanTypes.VsanPerfQuerySpec, 0, len(group.ids))
+			for _, entityRefID := range group.ids {
+				spec := vsanTypes.VsanPerfQuerySpec{
+					EntityRefId: fmt.Sprintf("%s:*", entityRefID),
+					StartTime:   &start,
+					EndTime:     &end,
+				}
+				if v.intervalSecs > 0 {
+					interval := v.intervalSecs
+					spec.Interval = &interval
+				}
+				querySpecs = append(querySpecs, spec)
+			}
+
+			req := vsanTypes.VsanPerfQueryPerf{
+				This:       vsanPerformanceManagerInstance,
+				QuerySpecs: querySpecs,
+				Cluster:    cluster.Reference(),
+			}
+			res, err := vsanPerfQueryPerfWithRetry(ctx, vsanClient, &req)
+			if err != nil {
+				if vsanNotEnabledFault(err) {
+					log.Printf("D! [inputs.vsphere][vSAN] vSAN is not enabled for this cluster, skipping")
+					return nil
+				}
+				// Surface the error and still try the other group for this
+				// window - but don't advance the checkpoint past it: windows
+				// are chronological, so a later window (or the other group)
+				// succeeding doesn't mean this data ever got collected, and
+				// persisting a checkpoint past it would permanently lose the
+				// gap, since the next poll's checkpoint() would resume after
+				// the failure and never retry it.
+				acc.AddError(fmt.Errorf("error querying vSAN %s entities for window %s -> %s: %w", group.label, start.Format(timeFormat), end.Format(timeFormat), err))
+				windowFailed = true
+				continue
+			}
+
+			for _, ret := range res.Returnval {
+				log.Printf("D! [inputs.vsphere][vSAN]\tSuccessfully Fetched data for Entity ==> %s:%d\n", ret.EntityRefId, len(ret.Value))
+				if ts := v.formatAndSendVsanMetric(ret, tags, acc, vmInventory); ts.After(windowMaxTs) {
+					windowMaxTs = ts
+				}
+			}
+		}
+
+		if !windowFailed && windowMaxTs.After(checkpointTs) {
+			checkpointTs = windowMaxTs
+		}
+		if windowFailed {
+			break
+		}
+	}
+	if !checkpointTs.IsZero() {
+		v.setCheckpoint(clusterMoid, checkpointTs)
+		if err := v.saveState(); err != nil {
+			acc.AddError(fmt.Errorf("error saving vsan_state_file: %w", err))
+		}
+	}
+	return nil
 }
 
 func getVsanTags(cluster objectRef, vcenter string) map[string]string {
@@ -138,18 +782,161 @@ func getVsanTags(cluster objectRef, vcenter string) map[string]string {
 	return tags
 }
 
+// collectVsanHealth queries the cluster-wide vSAN health summary and emits
+// it as the vsphere_cluster_vsan_health measurement.
+func (v *VsanCollector) collectVsanHealth(ctx context.Context, vsanClient *soap.Client, cluster *object.ClusterComputeResource, tags map[string]string, acc telegraf.Accumulator) error {
+	req := vsanTypes.VsanQueryVcClusterHealthSummary{
+		This:    vsanHealthSystemInstance,
+		Cluster: cluster.Reference(),
+	}
+	res, err := methods.VsanQueryVcClusterHealthSummary(ctx, vsanClient, &req)
+	if err != nil {
+		if vsanNotEnabledFault(err) {
+			return nil
+		}
+		return err
+	}
+	acc.AddFields(vsanHealthMetricsName, vsanHealthFields(res.Returnval), tags)
+	return nil
+}
+
+// vsanHealthFields maps a VsanClusterHealthSummary onto the fields emitted
+// for the vsphere_cluster_vsan_health measurement.
+func vsanHealthFields(summary vsanTypes.VsanClusterHealthSummary) map[string]interface{} {
+	return map[string]interface{}{
+		"overall_health": summary.OverallHealth,
+	}
+}
+
+// collectVsanCapacity queries cluster-wide vSAN capacity and dedup/
+// compression usage and emits it as the vsphere_cluster_vsan_capacity
+// measurement.
+func (v *VsanCollector) collectVsanCapacity(ctx context.Context, vsanClient *soap.Client, cluster *object.ClusterComputeResource, tags map[string]string, acc telegraf.Accumulator) error {
+	req := vsanTypes.VsanQuerySpaceUsage{
+		This:    vsanSpaceReportSystemInstance,
+		Cluster: cluster.Reference(),
+	}
+	res, err := methods.VsanQuerySpaceUsage(ctx, vsanClient, &req)
+	if err != nil {
+		if vsanNotEnabledFault(err) {
+			return nil
+		}
+		return err
+	}
+	acc.AddFields(vsanCapacityMetricsName, vsanCapacityFields(res.Returnval), tags)
+	return nil
+}
+
+// vsanCapacityFields maps a VsanObjectSpaceSummary onto the fields emitted
+// for the vsphere_cluster_vsan_capacity measurement.
+func vsanCapacityFields(summary vsanTypes.VsanObjectSpaceSummary) map[string]interface{} {
+	return map[string]interface{}{
+		"total_bytes": summary.TotalCapacityB,
+		"free_bytes":  summary.FreeCapacityB,
+		"dedup_ratio": summary.DedupRatio,
+	}
+}
+
+// collectVsanResync queries the cluster-wide vSAN resync (data migration)
+// summary and emits it as the vsphere_cluster_vsan_resync measurement.
+func (v *VsanCollector) collectVsanResync(ctx context.Context, vsanClient *soap.Client, cluster *object.ClusterComputeResource, tags map[string]string, acc telegraf.Accumulator) error {
+	req := vsanTypes.VsanQueryVcClusterResyncSummary{
+		This:    vsanHealthSystemInstance,
+		Cluster: cluster.Reference(),
+	}
+	res, err := methods.VsanQueryVcClusterResyncSummary(ctx, vsanClient, &req)
+	if err != nil {
+		if vsanNotEnabledFault(err) {
+			return nil
+		}
+		return err
+	}
+	acc.AddFields(vsanResyncMetricsName, vsanResyncFields(res.Returnval), tags)
+	return nil
+}
+
+// vsanResyncFields maps a VsanResyncSummary onto the fields emitted for the
+// vsphere_cluster_vsan_resync measurement.
+func vsanResyncFields(summary vsanTypes.VsanResyncSummary) map[string]interface{} {
+	return map[string]interface{}{
+		"resync_bytes_to_sync": summary.TotalBytesToSync,
+		"resync_eta_seconds":   summary.TotalRecoveryETA,
+	}
+}
+
+// vsanClusterObjectName is a seam over (*object.ClusterComputeResource).
+// ObjectName so tests/benchmarks driving CollectVsan/CollectVsanClusters
+// don't need a live property collector.
+var vsanClusterObjectName = func(ctx context.Context, cluster *object.ClusterComputeResource) (string, error) {
+	return cluster.ObjectName(ctx)
+}
+
+// vsanNewServiceClient is a seam over (*vim25.Client).NewServiceClient so
+// tests/benchmarks driving CollectVsan/CollectVsanClusters don't need a live
+// vim25.Client.
+var vsanNewServiceClient = func(client *vim25.Client) *soap.Client {
+	return client.NewServiceClient(vsanPath, vsanNamespace)
+}
+
 // CollectVsan invokes the vSAN Performance Manager on the ClusterComputeResource from the input.
-func CollectVsan(ctx context.Context, client *vim25.Client, clusterObj objectRef, wg *sync.WaitGroup, vcenter string, acc telegraf.Accumulator) {
+func (v *VsanCollector) CollectVsan(ctx context.Context, client *vim25.Client, clusterObj objectRef, wg *sync.WaitGroup, vcenter string, acc telegraf.Accumulator) {
 	defer wg.Done()
 	cluster := object.NewClusterComputeResource(client, clusterObj.ref)
-	if clusterName, err := cluster.ObjectName(ctx); err != nil {
+	if clusterName, err := vsanClusterObjectName(ctx, cluster); err != nil {
 		log.Printf("D! [inputs.vsphere][vSAN] Starting vSAN Collection for %s", clusterName)
 	}
 
 	tags := getVsanTags(clusterObj, vcenter)
 	log.Printf("D! [inputs.vsphere][vSAN] Tags for vSAN: %s", tags)
 
+	var vmInventory *vsanVMInventory
+	if v.metricInclude != nil {
+		inv, err := v.vmInventoryFor(ctx, client, cluster)
+		if err != nil {
+			log.Printf("E! [inputs.vsphere][vSAN] Failed to build VM inventory for tag enrichment: %s", err)
+		} else {
+			vmInventory = inv
+		}
+	}
+
 	// vSAN Client
-	vsanClient := client.NewServiceClient(vsanPath, vsanNamespace)
-	getAllVsanMetrics(ctx, vsanClient, cluster, tags, acc)
+	vsanClient := vsanNewServiceClient(client)
+	v.ensureCounterMetadata(ctx, vsanClient)
+	if err := v.getAllVsanMetrics(ctx, vsanClient, cluster, vmInventory, tags, acc); err != nil {
+		acc.AddError(fmt.Errorf("error collecting vSAN metrics for cluster %q: %w", clusterObj.name, err))
+	}
+
+	if v.collectHealth {
+		if err := v.collectVsanHealth(ctx, vsanClient, cluster, tags, acc); err != nil {
+			acc.AddError(fmt.Errorf("error collecting vSAN health for cluster %q: %w", clusterObj.name, err))
+		}
+	}
+	if v.collectCapacity {
+		if err := v.collectVsanCapacity(ctx, vsanClient, cluster, tags, acc); err != nil {
+			acc.AddError(fmt.Errorf("error collecting vSAN capacity for cluster %q: %w", clusterObj.name, err))
+		}
+	}
+	if v.collectResync {
+		if err := v.collectVsanResync(ctx, vsanClient, cluster, tags, acc); err != nil {
+			acc.AddError(fmt.Errorf("error collecting vSAN resync data for cluster %q: %w", clusterObj.name, err))
+		}
+	}
+}
+
+// CollectVsanClusters drives CollectVsan across clusters, bounding the
+// number of clusters collected from concurrently to vsan_concurrency (see
+// VsanCollectorConfig.Concurrency). It blocks until every cluster has been
+// collected.
+func (v *VsanCollector) CollectVsanClusters(ctx context.Context, client *vim25.Client, clusters []objectRef, vcenter string, acc telegraf.Accumulator) {
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, v.concurrency)
+	for _, clusterObj := range clusters {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(clusterObj objectRef) {
+			defer func() { <-sem }()
+			v.CollectVsan(ctx, client, clusterObj, &wg, vcenter, acc)
+		}(clusterObj)
+	}
+	wg.Wait()
 }
@@ -0,0 +1,25 @@
+package methods
+
+import (
+	"context"
+
+	"github.com/influxdata/telegraf/plugins/inputs/vsphere/vsan/types"
+	"github.com/vmware/govmomi/vim25/soap"
+)
+
+type VsanPerfGetSupportedEntityTypesBody struct {
+	Req    *types.VsanPerfGetSupportedEntityTypes         `xml:"urn:vsan VsanPerfGetSupportedEntityTypes,omitempty"`
+	Res    *types.VsanPerfGetSupportedEntityTypesResponse `xml:"urn:vsan VsanPerfGetSupportedEntityTypesResponse,omitempty"`
+	Fault_ *soap.Fault                                    `xml:",omitempty"`
+}
+
+func (b *VsanPerfGetSupportedEntityTypesBody) Fault() *soap.Fault { return b.Fault_ }
+
+func VsanPerfGetSupportedEntityTypes(ctx context.Context, r soap.RoundTripper, req *types.VsanPerfGetSupportedEntityTypes) (*types.VsanPerfGetSupportedEntityTypesResponse, error) {
+	var reqBody, resBody VsanPerfGetSupportedEntityTypesBody
+	reqBody.Req = req
+	if err := r.RoundTrip(ctx, &reqBody, &resBody); err != nil {
+		return nil, err
+	}
+	return resBody.Res, nil
+}
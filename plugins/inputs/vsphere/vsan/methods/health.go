@@ -0,0 +1,59 @@
+package methods
+
+import (
+	"context"
+
+	"github.com/influxdata/telegraf/plugins/inputs/vsphere/vsan/types"
+	"github.com/vmware/govmomi/vim25/soap"
+)
+
+type VsanQueryVcClusterHealthSummaryBody struct {
+	Req    *types.VsanQueryVcClusterHealthSummary         `xml:"urn:vsan VsanQueryVcClusterHealthSummary,omitempty"`
+	Res    *types.VsanQueryVcClusterHealthSummaryResponse `xml:"urn:vsan VsanQueryVcClusterHealthSummaryResponse,omitempty"`
+	Fault_ *soap.Fault                                    `xml:",omitempty"`
+}
+
+func (b *VsanQueryVcClusterHealthSummaryBody) Fault() *soap.Fault { return b.Fault_ }
+
+func VsanQueryVcClusterHealthSummary(ctx context.Context, r soap.RoundTripper, req *types.VsanQueryVcClusterHealthSummary) (*types.VsanQueryVcClusterHealthSummaryResponse, error) {
+	var reqBody, resBody VsanQueryVcClusterHealthSummaryBody
+	reqBody.Req = req
+	if err := r.RoundTrip(ctx, &reqBody, &resBody); err != nil {
+		return nil, err
+	}
+	return resBody.Res, nil
+}
+
+type VsanQuerySpaceUsageBody struct {
+	Req    *types.VsanQuerySpaceUsage         `xml:"urn:vsan VsanQuerySpaceUsage,omitempty"`
+	Res    *types.VsanQuerySpaceUsageResponse `xml:"urn:vsan VsanQuerySpaceUsageResponse,omitempty"`
+	Fault_ *soap.Fault                        `xml:",omitempty"`
+}
+
+func (b *VsanQuerySpaceUsageBody) Fault() *soap.Fault { return b.Fault_ }
+
+func VsanQuerySpaceUsage(ctx context.Context, r soap.RoundTripper, req *types.VsanQuerySpaceUsage) (*types.VsanQuerySpaceUsageResponse, error) {
+	var reqBody, resBody VsanQuerySpaceUsageBody
+	reqBody.Req = req
+	if err := r.RoundTrip(ctx, &reqBody, &resBody); err != nil {
+		return nil, err
+	}
+	return resBody.Res, nil
+}
+
+type VsanQueryVcClusterResyncSummaryBody struct {
+	Req    *types.VsanQueryVcClusterResyncSummary         `xml:"urn:vsan VsanQueryVcClusterResyncSummary,omitempty"`
+	Res    *types.VsanQueryVcClusterResyncSummaryResponse `xml:"urn:vsan VsanQueryVcClusterResyncSummaryResponse,omitempty"`
+	Fault_ *soap.Fault                                    `xml:",omitempty"`
+}
+
+func (b *VsanQueryVcClusterResyncSummaryBody) Fault() *soap.Fault { return b.Fault_ }
+
+func VsanQueryVcClusterResyncSummary(ctx context.Context, r soap.RoundTripper, req *types.VsanQueryVcClusterResyncSummary) (*types.VsanQueryVcClusterResyncSummaryResponse, error) {
+	var reqBody, resBody VsanQueryVcClusterResyncSummaryBody
+	reqBody.Req = req
+	if err := r.RoundTrip(ctx, &reqBody, &resBody); err != nil {
+		return nil, err
+	}
+	return resBody.Res, nil
+}
@@ -0,0 +1,25 @@
+package methods
+
+import (
+	"context"
+
+	"github.com/influxdata/telegraf/plugins/inputs/vsphere/vsan/types"
+	"github.com/vmware/govmomi/vim25/soap"
+)
+
+type VsanPerfQueryPerfBody struct {
+	Req    *types.VsanPerfQueryPerf         `xml:"urn:vsan VsanPerfQueryPerf,omitempty"`
+	Res    *types.VsanPerfQueryPerfResponse `xml:"urn:vsan VsanPerfQueryPerfResponse,omitempty"`
+	Fault_ *soap.Fault                      `xml:",omitempty"`
+}
+
+func (b *VsanPerfQueryPerfBody) Fault() *soap.Fault { return b.Fault_ }
+
+func VsanPerfQueryPerf(ctx context.Context, r soap.RoundTripper, req *types.VsanPerfQueryPerf) (*types.VsanPerfQueryPerfResponse, error) {
+	var reqBody, resBody VsanPerfQueryPerfBody
+	reqBody.Req = req
+	if err := r.RoundTrip(ctx, &reqBody, &resBody); err != nil {
+		return nil, err
+	}
+	return resBody.Res, nil
+}
@@ -0,0 +1,30 @@
+package types
+
+import "github.com/vmware/govmomi/vim25/types"
+
+// VsanPerfGetSupportedEntityTypes requests the set of entity types and
+// their counter metadata (including declared unit) known to the vSAN
+// Performance Manager.
+type VsanPerfGetSupportedEntityTypes struct {
+	This types.ManagedObjectReference `xml:"_this"`
+}
+
+type VsanPerfGetSupportedEntityTypesResponse struct {
+	Returnval []VsanPerfEntityType `xml:"returnval"`
+}
+
+// VsanPerfEntityType describes one entity type (e.g. "cluster-domclient")
+// and the counters it reports.
+type VsanPerfEntityType struct {
+	Name     string                    `xml:"name"`
+	Counters []VsanPerfCounterMetadata `xml:"counters"`
+}
+
+// VsanPerfCounterMetadata mirrors vim.cluster.VsanPerfMetricId, trimmed to
+// the fields needed to attach a unit tag and normalize values the way the
+// core vSphere collector does for PerfCounterInfo.
+type VsanPerfCounterMetadata struct {
+	Group string `xml:"group,omitempty"`
+	Label string `xml:"label"`
+	Unit  string `xml:"unit,omitempty"`
+}
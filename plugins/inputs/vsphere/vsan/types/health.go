@@ -0,0 +1,66 @@
+package types
+
+import "github.com/vmware/govmomi/vim25/types"
+
+// VsanQueryVcClusterHealthSummary requests the cluster-wide vSAN health
+// summary from the VsanVcClusterHealthSystem managed object.
+type VsanQueryVcClusterHealthSummary struct {
+	This            types.ManagedObjectReference `xml:"_this"`
+	Cluster         types.ManagedObjectReference `xml:"cluster"`
+	Fields          []string                     `xml:"fields,omitempty"`
+	FetchFromCache  *bool                        `xml:"fetchFromCache"`
+	IncludeObjUuids *bool                        `xml:"includeObjUuids"`
+}
+
+type VsanQueryVcClusterHealthSummaryResponse struct {
+	Returnval VsanClusterHealthSummary `xml:"returnval"`
+}
+
+// VsanClusterHealthSummary is a trimmed-down view of
+// vim.cluster.VsanClusterHealthSummary, carrying only the fields the
+// telegraf input surfaces as the vsphere_cluster_vsan_health measurement.
+type VsanClusterHealthSummary struct {
+	OverallHealth            string `xml:"overallHealth"`
+	OverallHealthDescription string `xml:"overallHealthDescription,omitempty"`
+}
+
+// VsanQuerySpaceUsage requests cluster-wide vSAN capacity and
+// deduplication/compression usage from the VsanSpaceReportSystem managed
+// object.
+type VsanQuerySpaceUsage struct {
+	This    types.ManagedObjectReference `xml:"_this"`
+	Cluster types.ManagedObjectReference `xml:"cluster"`
+}
+
+type VsanQuerySpaceUsageResponse struct {
+	Returnval VsanObjectSpaceSummary `xml:"returnval"`
+}
+
+// VsanObjectSpaceSummary mirrors vim.vsan.VsanObjectSpaceSummary, trimmed
+// to the fields the telegraf input surfaces as the
+// vsphere_cluster_vsan_capacity measurement.
+type VsanObjectSpaceSummary struct {
+	TotalCapacityB int64   `xml:"totalCapacityB"`
+	FreeCapacityB  int64   `xml:"freeCapacityB"`
+	DedupRatio     float32 `xml:"dedupRatio,omitempty"`
+}
+
+// VsanQueryVcClusterResyncSummary requests the cluster-wide vSAN resync
+// (data migration) summary from the VsanVcClusterHealthSystem managed
+// object.
+type VsanQueryVcClusterResyncSummary struct {
+	This    types.ManagedObjectReference `xml:"_this"`
+	Cluster types.ManagedObjectReference `xml:"cluster"`
+}
+
+type VsanQueryVcClusterResyncSummaryResponse struct {
+	Returnval VsanResyncSummary `xml:"returnval"`
+}
+
+// VsanResyncSummary mirrors vim.cluster.VsanObjectResyncSummary, trimmed
+// to the fields the telegraf input surfaces as the
+// vsphere_cluster_vsan_resync measurement.
+type VsanResyncSummary struct {
+	TotalBytesToSync int64 `xml:"totalBytesToSync"`
+	TotalRecoveryETA int64 `xml:"totalRecoveryETA"` // seconds
+}
@@ -0,0 +1,53 @@
+package types
+
+import (
+	"time"
+
+	"github.com/vmware/govmomi/vim25/types"
+)
+
+// VsanPerfQuerySpec mirrors vim.cluster.VsanPerfQuerySpec. Interval, when
+// set, requests a specific metricsCollectInterval (in seconds) from the
+// vSAN Performance Manager instead of accepting its default (300s).
+type VsanPerfQuerySpec struct {
+	EntityRefId string     `xml:"entityRefId"`
+	StartTime   *time.Time `xml:"startTime,omitempty"`
+	EndTime     *time.Time `xml:"endTime,omitempty"`
+	Labels      []string   `xml:"labels,omitempty"`
+	Interval    *int32     `xml:"interval,omitempty"`
+}
+
+// VsanPerfQueryPerf mirrors vim.cluster.VsanPerformanceManager#vsanPerfQueryPerf.
+type VsanPerfQueryPerf struct {
+	This       types.ManagedObjectReference `xml:"_this"`
+	QuerySpecs []VsanPerfQuerySpec          `xml:"querySpecs"`
+	Cluster    types.ManagedObjectReference `xml:"cluster"`
+}
+
+type VsanPerfQueryPerfResponse struct {
+	Returnval []VsanPerfEntityMetricCSV `xml:"returnval"`
+}
+
+// VsanPerfEntityMetricCSV mirrors vim.cluster.VsanPerfEntityMetricCSV - see
+// the comment on formatAndSendVsanMetric in vsan.go for an example payload.
+type VsanPerfEntityMetricCSV struct {
+	EntityRefId string                    `xml:"entityRefId"`
+	SampleInfo  string                    `xml:"sampleInfo"`
+	Value       []VsanPerfMetricSeriesCSV `xml:"value,omitempty"`
+}
+
+// VsanPerfMetricSeriesCSV mirrors vim.cluster.VsanPerfMetricSeriesCSV.
+type VsanPerfMetricSeriesCSV struct {
+	MetricId VsanPerfMetricId `xml:"metricId"`
+	Values   string           `xml:"values"`
+}
+
+// VsanPerfMetricId mirrors vim.cluster.VsanPerfMetricId.
+type VsanPerfMetricId struct {
+	Label                  string `xml:"label"`
+	Group                  string `xml:"group,omitempty"`
+	Description            string `xml:"description,omitempty"`
+	MetricsCollectInterval int32  `xml:"metricsCollectInterval,omitempty"`
+	RollupType             string `xml:"rollupType,omitempty"`
+	StatsType              string `xml:"statsType,omitempty"`
+}
@@ -0,0 +1,260 @@
+package vsphere
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/influxdata/telegraf/plugins/inputs/vsphere/vsan/types"
+	"github.com/influxdata/telegraf/testutil"
+	"github.com/vmware/govmomi/object"
+	"github.com/vmware/govmomi/vim25"
+	"github.com/vmware/govmomi/vim25/soap"
+)
+
+// fakeVsanLatency simulates the round-trip cost of one VsanPerfQueryPerf
+// call against a real vCenter, so the benchmarks below show a realistic
+// wall-clock difference between one call per entity and one batched call.
+const fakeVsanLatency = 2 * time.Millisecond
+
+// newFakeVsanPerfQueryPerf returns a stand-in for methods.VsanPerfQueryPerf
+// that counts calls in callCount and echoes back one VsanPerfEntityMetricCSV
+// per requested QuerySpec, without any network I/O. Each sample is stamped
+// with the spec's StartTime, so the checkpoint stays close to now and every
+// poll of the same cluster covers a single query window.
+func newFakeVsanPerfQueryPerf(callCount *int64) func(context.Context, soap.RoundTripper, *types.VsanPerfQueryPerf) (*types.VsanPerfQueryPerfResponse, error) {
+	return func(_ context.Context, _ soap.RoundTripper, req *types.VsanPerfQueryPerf) (*types.VsanPerfQueryPerfResponse, error) {
+		atomic.AddInt64(callCount, 1)
+		time.Sleep(fakeVsanLatency)
+		res := &types.VsanPerfQueryPerfResponse{}
+		for _, spec := range req.QuerySpecs {
+			res.Returnval = append(res.Returnval, types.VsanPerfEntityMetricCSV{
+				EntityRefId: spec.EntityRefId + ":fake-uuid",
+				SampleInfo:  spec.StartTime.UTC().Format("2006-01-02 15:04:05"),
+				Value: []types.VsanPerfMetricSeriesCSV{
+					{MetricId: types.VsanPerfMetricId{Label: "iopsRead"}, Values: "1"},
+				},
+			})
+		}
+		return res, nil
+	}
+}
+
+func benchmarkCluster(b *testing.B) *object.ClusterComputeResource {
+	b.Helper()
+	client := &vim25.Client{}
+	ref := objectRef{name: "bench-cluster", dcname: "bench-dc"}
+	return object.NewClusterComputeResource(client, ref.ref)
+}
+
+// benchmarkVsanCollection drives getAllVsanMetrics across clusterCount
+// clusters and reports the number of VsanPerfQueryPerf calls made.
+func benchmarkVsanCollection(b *testing.B, collector *VsanCollector, clusterCount int) int64 {
+	b.Helper()
+	var callCount int64
+	orig := vsanPerfQueryPerf
+	vsanPerfQueryPerf = newFakeVsanPerfQueryPerf(&callCount)
+	defer func() { vsanPerfQueryPerf = orig }()
+
+	cluster := benchmarkCluster(b)
+	ctx := context.Background()
+	acc := &testutil.Accumulator{}
+	tags := map[string]string{"clustername": "bench-cluster"}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for c := 0; c < clusterCount; c++ {
+			if err := collector.getAllVsanMetrics(ctx, nil, cluster, nil, tags, acc); err != nil {
+				b.Fatalf("getAllVsanMetrics: %s", err)
+			}
+		}
+	}
+	return callCount
+}
+
+// BenchmarkVsanCollectionSequential simulates the pre-batching behavior -
+// one VsanPerfQueryPerf call per entity ref ID - by including every
+// VM-level entity ref ID and issuing them one at a time.
+func BenchmarkVsanCollectionSequential(b *testing.B) {
+	collector, err := NewVsanCollector(VsanCollectorConfig{MetricInclude: []string{"*"}})
+	if err != nil {
+		b.Fatalf("NewVsanCollector: %s", err)
+	}
+
+	var callCount int64
+	orig := vsanPerfQueryPerf
+	fake := newFakeVsanPerfQueryPerf(&callCount)
+	vsanPerfQueryPerf = func(ctx context.Context, r soap.RoundTripper, req *types.VsanPerfQueryPerf) (*types.VsanPerfQueryPerfResponse, error) {
+		// Split the batched request back into one call per QuerySpec to
+		// reproduce the call volume of the original implementation.
+		res := &types.VsanPerfQueryPerfResponse{}
+		for _, spec := range req.QuerySpecs {
+			single := *req
+			single.QuerySpecs = []types.VsanPerfQuerySpec{spec}
+			singleRes, err := fake(ctx, r, &single)
+			if err != nil {
+				return nil, err
+			}
+			res.Returnval = append(res.Returnval, singleRes.Returnval...)
+		}
+		return res, nil
+	}
+	defer func() { vsanPerfQueryPerf = orig }()
+
+	cluster := benchmarkCluster(b)
+	ctx := context.Background()
+	acc := &testutil.Accumulator{}
+	tags := map[string]string{"clustername": "bench-cluster"}
+	const clusterCount = 10
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for c := 0; c < clusterCount; c++ {
+			if err := collector.getAllVsanMetrics(ctx, nil, cluster, nil, tags, acc); err != nil {
+				b.Fatalf("getAllVsanMetrics: %s", err)
+			}
+		}
+	}
+	b.ReportMetric(float64(callCount)/float64(b.N), "calls/op")
+}
+
+// BenchmarkVsanCollectionBatched exercises the current implementation,
+// which combines each of the core and VM-level entity ref ID groups into
+// one VsanPerfQueryPerf call per cluster per window (two calls per cluster
+// here, since MetricInclude opts into the VM-level group), instead of one call
+// per entity ref ID.
+func BenchmarkVsanCollectionBatched(b *testing.B) {
+	collector, err := NewVsanCollector(VsanCollectorConfig{MetricInclude: []string{"*"}})
+	if err != nil {
+		b.Fatalf("NewVsanCollector: %s", err)
+	}
+	const clusterCount = 10
+	callCount := benchmarkVsanCollection(b, collector, clusterCount)
+	b.ReportMetric(float64(callCount)/float64(b.N), "calls/op")
+}
+
+// fakeVsanClusters returns n distinct objectRefs suitable for driving
+// CollectVsanClusters without a real vCenter.
+func fakeVsanClusters(n int) []objectRef {
+	clusters := make([]objectRef, n)
+	for i := 0; i < n; i++ {
+		clusters[i] = objectRef{name: fmt.Sprintf("bench-cluster-%d", i), dcname: "bench-dc"}
+	}
+	return clusters
+}
+
+// stubVsanClusterSeams overrides the seams CollectVsan uses to talk to a
+// real vCenter (cluster name lookup, building a vSAN service client, and
+// fetching counter metadata) so CollectVsanClusters can be driven
+// end-to-end against a zero-value vim25.Client. It returns a restore func.
+func stubVsanClusterSeams(tb testing.TB) func() {
+	tb.Helper()
+	origName := vsanClusterObjectName
+	origClient := vsanNewServiceClient
+	origCounters := vsanPerfGetSupportedEntityTypes
+
+	vsanClusterObjectName = func(context.Context, *object.ClusterComputeResource) (string, error) {
+		return "", nil
+	}
+	vsanNewServiceClient = func(*vim25.Client) *soap.Client {
+		return nil
+	}
+	vsanPerfGetSupportedEntityTypes = func(context.Context, soap.RoundTripper, *types.VsanPerfGetSupportedEntityTypes) (*types.VsanPerfGetSupportedEntityTypesResponse, error) {
+		return &types.VsanPerfGetSupportedEntityTypesResponse{}, nil
+	}
+
+	return func() {
+		vsanClusterObjectName = origName
+		vsanNewServiceClient = origClient
+		vsanPerfGetSupportedEntityTypes = origCounters
+	}
+}
+
+// TestCollectVsanClustersBoundsConcurrency drives CollectVsanClusters
+// across more clusters than vsan_concurrency allows and verifies the
+// worker pool never lets more than vsan_concurrency collections run at
+// once.
+func TestCollectVsanClustersBoundsConcurrency(t *testing.T) {
+	const concurrency = 3
+	const clusterCount = 10
+
+	collector, err := NewVsanCollector(VsanCollectorConfig{Concurrency: concurrency})
+	if err != nil {
+		t.Fatalf("NewVsanCollector: %s", err)
+	}
+	defer stubVsanClusterSeams(t)()
+
+	var inFlight, maxInFlight int64
+	orig := vsanPerfQueryPerf
+	vsanPerfQueryPerf = func(context.Context, soap.RoundTripper, *types.VsanPerfQueryPerf) (*types.VsanPerfQueryPerfResponse, error) {
+		n := atomic.AddInt64(&inFlight, 1)
+		for {
+			cur := atomic.LoadInt64(&maxInFlight)
+			if n <= cur || atomic.CompareAndSwapInt64(&maxInFlight, cur, n) {
+				break
+			}
+		}
+		time.Sleep(5 * time.Millisecond)
+		atomic.AddInt64(&inFlight, -1)
+		return &types.VsanPerfQueryPerfResponse{}, nil
+	}
+	defer func() { vsanPerfQueryPerf = orig }()
+
+	acc := &testutil.Accumulator{}
+	collector.CollectVsanClusters(context.Background(), &vim25.Client{}, fakeVsanClusters(clusterCount), "bench-vc", acc)
+
+	if len(acc.Errors) != 0 {
+		t.Fatalf("unexpected errors: %v", acc.Errors)
+	}
+	if maxInFlight > concurrency {
+		t.Errorf("observed %d concurrent vSAN queries, want at most vsan_concurrency (%d)", maxInFlight, concurrency)
+	}
+	if maxInFlight < 2 {
+		t.Errorf("observed only %d concurrent vSAN query at once, concurrency does not appear to be exercised", maxInFlight)
+	}
+}
+
+// benchmarkCollectVsanClusters drives CollectVsanClusters across
+// clusterCount clusters with the given vsan_concurrency, using a fake
+// VsanPerfQueryPerf that sleeps fakeVsanLatency per call to simulate a real
+// round-trip.
+func benchmarkCollectVsanClusters(b *testing.B, concurrency, clusterCount int) {
+	b.Helper()
+	collector, err := NewVsanCollector(VsanCollectorConfig{Concurrency: concurrency})
+	if err != nil {
+		b.Fatalf("NewVsanCollector: %s", err)
+	}
+	defer stubVsanClusterSeams(b)()
+
+	orig := vsanPerfQueryPerf
+	vsanPerfQueryPerf = func(context.Context, soap.RoundTripper, *types.VsanPerfQueryPerf) (*types.VsanPerfQueryPerfResponse, error) {
+		time.Sleep(fakeVsanLatency)
+		return &types.VsanPerfQueryPerfResponse{}, nil
+	}
+	defer func() { vsanPerfQueryPerf = orig }()
+
+	client := &vim25.Client{}
+	clusters := fakeVsanClusters(clusterCount)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		acc := &testutil.Accumulator{}
+		collector.CollectVsanClusters(context.Background(), client, clusters, "bench-vc", acc)
+	}
+}
+
+// BenchmarkCollectVsanClustersSequential collects from multiple clusters
+// one at a time (vsan_concurrency=1), establishing the wall-clock baseline
+// BenchmarkCollectVsanClustersConcurrent improves on below.
+func BenchmarkCollectVsanClustersSequential(b *testing.B) {
+	benchmarkCollectVsanClusters(b, 1, 10)
+}
+
+// BenchmarkCollectVsanClustersConcurrent collects from the same clusters
+// with vsan_concurrency=5, demonstrating the wall-clock reduction from
+// collecting clusters in parallel.
+func BenchmarkCollectVsanClustersConcurrent(b *testing.B) {
+	benchmarkCollectVsanClusters(b, 5, 10)
+}
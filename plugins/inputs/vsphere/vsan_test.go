@@ -0,0 +1,514 @@
+package vsphere
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	vsanTypes "github.com/influxdata/telegraf/plugins/inputs/vsphere/vsan/types"
+	"github.com/influxdata/telegraf/testutil"
+	"github.com/vmware/govmomi/object"
+	"github.com/vmware/govmomi/vim25"
+	"github.com/vmware/govmomi/vim25/soap"
+)
+
+func TestEnrichVsanVMTags(t *testing.T) {
+	inv := &vsanVMInventory{
+		byVMUUID: map[string]vsanVMInfo{
+			"vm-uuid-1": {name: "vm1", moid: "vm-100"},
+		},
+	}
+
+	tests := []struct {
+		name       string
+		entityName string
+		uuid       string
+		inv        *vsanVMInventory
+		want       map[string]string
+	}{
+		{
+			name:       "nil inventory is a no-op",
+			entityName: "virtual-machine",
+			uuid:       "vm-uuid-1",
+			inv:        nil,
+			want:       map[string]string{},
+		},
+		{
+			name:       "virtual-machine entity resolves vmname and vm_moid",
+			entityName: "virtual-machine",
+			uuid:       "vm-uuid-1",
+			inv:        inv,
+			want:       map[string]string{"vmname": "vm1", "vm_moid": "vm-100"},
+		},
+		{
+			name:       "virtual-machine-vsan-vnic entity resolves vmname and vm_moid",
+			entityName: "virtual-machine-vsan-vnic",
+			uuid:       "vm-uuid-1",
+			inv:        inv,
+			want:       map[string]string{"vmname": "vm1", "vm_moid": "vm-100"},
+		},
+		{
+			name:       "unknown vm uuid is a no-op",
+			entityName: "virtual-machine",
+			uuid:       "unknown-uuid",
+			inv:        inv,
+			want:       map[string]string{},
+		},
+		{
+			name:       "virtual-disk entity splits vm uuid and disk uuid",
+			entityName: "virtual-disk",
+			uuid:       "vm-uuid-1|disk-uuid-1",
+			inv:        inv,
+			want:       map[string]string{"vmname": "vm1", "vm_moid": "vm-100", "disk_uuid": "disk-uuid-1"},
+		},
+		{
+			name:       "vscsi entity splits vm uuid and disk uuid",
+			entityName: "vscsi",
+			uuid:       "vm-uuid-1|disk-uuid-2",
+			inv:        inv,
+			want:       map[string]string{"vmname": "vm1", "vm_moid": "vm-100", "disk_uuid": "disk-uuid-2"},
+		},
+		{
+			name:       "virtual-disk entity without a disk uuid separator is a no-op",
+			entityName: "virtual-disk",
+			uuid:       "vm-uuid-1",
+			inv:        inv,
+			want:       map[string]string{},
+		},
+		{
+			name:       "cluster-domclient entity is a no-op",
+			entityName: "cluster-domclient",
+			uuid:       "vm-uuid-1",
+			inv:        inv,
+			want:       map[string]string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tags := map[string]string{}
+			enrichVsanVMTags(tt.entityName, tt.uuid, tt.inv, tags)
+			if len(tags) != len(tt.want) {
+				t.Fatalf("got tags %v, want %v", tags, tt.want)
+			}
+			for k, v := range tt.want {
+				if tags[k] != v {
+					t.Errorf("tag %q = %q, want %q", k, tags[k], v)
+				}
+			}
+		})
+	}
+}
+
+func TestChunkVsanWindow(t *testing.T) {
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name  string
+		start time.Time
+		end   time.Time
+		want  []vsanQueryWindow
+	}{
+		{
+			name:  "empty range returns no windows",
+			start: base,
+			end:   base,
+			want:  nil,
+		},
+		{
+			name:  "end before start returns no windows",
+			start: base,
+			end:   base.Add(-time.Minute),
+			want:  nil,
+		},
+		{
+			name:  "range shorter than max window returns a single window",
+			start: base,
+			end:   base.Add(30 * time.Minute),
+			want: []vsanQueryWindow{
+				{start: base, end: base.Add(30 * time.Minute)},
+			},
+		},
+		{
+			name:  "range exactly one max window returns a single window",
+			start: base,
+			end:   base.Add(vsanMaxQueryWindow),
+			want: []vsanQueryWindow{
+				{start: base, end: base.Add(vsanMaxQueryWindow)},
+			},
+		},
+		{
+			name:  "range over one max window is split, with a short final window",
+			start: base,
+			end:   base.Add(vsanMaxQueryWindow + 15*time.Minute),
+			want: []vsanQueryWindow{
+				{start: base, end: base.Add(vsanMaxQueryWindow)},
+				{start: base.Add(vsanMaxQueryWindow), end: base.Add(vsanMaxQueryWindow + 15*time.Minute)},
+			},
+		},
+		{
+			name:  "range over two max windows is split into three",
+			start: base,
+			end:   base.Add(2*vsanMaxQueryWindow + time.Minute),
+			want: []vsanQueryWindow{
+				{start: base, end: base.Add(vsanMaxQueryWindow)},
+				{start: base.Add(vsanMaxQueryWindow), end: base.Add(2 * vsanMaxQueryWindow)},
+				{start: base.Add(2 * vsanMaxQueryWindow), end: base.Add(2*vsanMaxQueryWindow + time.Minute)},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := chunkVsanWindow(tt.start, tt.end)
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %d windows, want %d: %v", len(got), len(tt.want), got)
+			}
+			for i := range got {
+				if !got[i].start.Equal(tt.want[i].start) || !got[i].end.Equal(tt.want[i].end) {
+					t.Errorf("window %d = %+v, want %+v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestVsanCollectorCheckpoint(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	t.Run("no checkpoint falls back to maxBackfill", func(t *testing.T) {
+		v := &VsanCollector{
+			maxBackfill: 2 * time.Hour,
+			state:       make(map[string]*vsanClusterCheckpoint),
+		}
+		got := v.checkpoint("cluster-1", now)
+		want := now.Add(-2 * time.Hour)
+		if !got.Equal(want) {
+			t.Errorf("checkpoint() = %v, want %v", got, want)
+		}
+	})
+
+	t.Run("no checkpoint and no maxBackfill falls back to 5 minutes", func(t *testing.T) {
+		v := &VsanCollector{
+			state: make(map[string]*vsanClusterCheckpoint),
+		}
+		got := v.checkpoint("cluster-1", now)
+		want := now.Add(-5 * time.Minute)
+		if !got.Equal(want) {
+			t.Errorf("checkpoint() = %v, want %v", got, want)
+		}
+	})
+
+	t.Run("existing checkpoint advances past the last sample so it isn't re-requested", func(t *testing.T) {
+		// The vSAN Performance Manager's query range is inclusive of
+		// startTime, so checkpoint() must not return the last sample's
+		// timestamp unmodified - doing so would re-request (and
+		// re-emit) that exact sample on every subsequent poll.
+		last := now.Add(-10 * time.Minute)
+		v := &VsanCollector{
+			state: map[string]*vsanClusterCheckpoint{
+				"cluster-1": {LastSampleTime: last},
+			},
+		}
+		got := v.checkpoint("cluster-1", now)
+		if got.Equal(last) || !got.After(last) {
+			t.Errorf("checkpoint() = %v, want a time strictly after %v (the boundary must be exclusive)", got, last)
+		}
+	})
+
+	t.Run("setCheckpoint only advances forward", func(t *testing.T) {
+		v := &VsanCollector{
+			state: make(map[string]*vsanClusterCheckpoint),
+		}
+		later := now
+		earlier := now.Add(-time.Hour)
+
+		v.setCheckpoint("cluster-1", later)
+		v.setCheckpoint("cluster-1", earlier)
+
+		got := v.checkpoint("cluster-1", now.Add(time.Hour))
+		if !got.Equal(later.Add(time.Second)) {
+			t.Errorf("checkpoint() = %v, want %v (setCheckpoint must not move it backwards)", got, later.Add(time.Second))
+		}
+	})
+}
+
+func TestNormalizeVsanUnit(t *testing.T) {
+	tests := []struct {
+		name      string
+		unit      string
+		value     float64
+		wantValue float64
+		wantUnit  string
+	}{
+		{name: "KBps converts to Bps", unit: "KBps", value: 2, wantValue: 2048, wantUnit: "Bps"},
+		{name: "MBps converts to Bps", unit: "MBps", value: 1, wantValue: 1024 * 1024, wantUnit: "Bps"},
+		{name: "microsec converts to ns", unit: "microsec", value: 5, wantValue: 5000, wantUnit: "ns"},
+		{name: "us converts to ns", unit: "us", value: 3, wantValue: 3000, wantUnit: "ns"},
+		{name: "unrecognized unit passes through unchanged", unit: "percent", value: 42, wantValue: 42, wantUnit: "percent"},
+		{name: "empty unit passes through unchanged", unit: "", value: 7, wantValue: 7, wantUnit: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotValue, gotUnit := normalizeVsanUnit(tt.unit, tt.value)
+			if gotValue != tt.wantValue || gotUnit != tt.wantUnit {
+				t.Errorf("normalizeVsanUnit(%q, %v) = (%v, %q), want (%v, %q)",
+					tt.unit, tt.value, gotValue, gotUnit, tt.wantValue, tt.wantUnit)
+			}
+		})
+	}
+}
+
+func TestVsanNotEnabledFault(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{name: "nil error is not the fault", err: nil, want: false},
+		{
+			name: "error whose message contains the fault string is recognized",
+			err:  errors.New("ServerFaultCode: vSAN is not enabled on this cluster"),
+			want: true,
+		},
+		{
+			name: "wrapped error whose message contains the fault string is recognized",
+			err:  fmt.Errorf("query failed: %w", errors.New("vSAN is not enabled")),
+			want: true,
+		},
+		{
+			name: "unrelated error is not the fault",
+			err:  errors.New("connection refused"),
+			want: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := vsanNotEnabledFault(tt.err); got != tt.want {
+				t.Errorf("vsanNotEnabledFault(%v) = %v, want %v", tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestVsanHealthFields(t *testing.T) {
+	summary := vsanTypes.VsanClusterHealthSummary{
+		OverallHealth:            "red",
+		OverallHealthDescription: "one or more issues detected",
+	}
+	want := map[string]interface{}{
+		"overall_health": "red",
+	}
+	if got := vsanHealthFields(summary); !reflect.DeepEqual(got, want) {
+		t.Errorf("vsanHealthFields() = %v, want %v", got, want)
+	}
+}
+
+func TestVsanCapacityFields(t *testing.T) {
+	summary := vsanTypes.VsanObjectSpaceSummary{
+		TotalCapacityB: 1000,
+		FreeCapacityB:  400,
+		DedupRatio:     1.5,
+	}
+	want := map[string]interface{}{
+		"total_bytes": int64(1000),
+		"free_bytes":  int64(400),
+		"dedup_ratio": float32(1.5),
+	}
+	if got := vsanCapacityFields(summary); !reflect.DeepEqual(got, want) {
+		t.Errorf("vsanCapacityFields() = %v, want %v", got, want)
+	}
+}
+
+func TestVsanResyncFields(t *testing.T) {
+	summary := vsanTypes.VsanResyncSummary{
+		TotalBytesToSync: 2048,
+		TotalRecoveryETA: 90,
+	}
+	want := map[string]interface{}{
+		"resync_bytes_to_sync": int64(2048),
+		"resync_eta_seconds":   int64(90),
+	}
+	if got := vsanResyncFields(summary); !reflect.DeepEqual(got, want) {
+		t.Errorf("vsanResyncFields() = %v, want %v", got, want)
+	}
+}
+
+// TestGetAllVsanMetricsPartialGroupFailure verifies that when the VM-level
+// entity group fails in a batched VsanPerfQueryPerf call, the core group's
+// metrics (cluster/host/disk/nic, queried in a separate call) are still
+// collected, the failure is surfaced via acc.AddError, and the checkpoint
+// isn't advanced - since the VM group's data for this window was never
+// actually collected, a checkpoint that skipped past it would mean it's
+// never retried.
+func TestGetAllVsanMetricsPartialGroupFailure(t *testing.T) {
+	collector, err := NewVsanCollector(VsanCollectorConfig{MetricInclude: []string{"*"}})
+	if err != nil {
+		t.Fatalf("NewVsanCollector: %s", err)
+	}
+
+	orig := vsanPerfQueryPerf
+	vsanPerfQueryPerf = func(_ context.Context, _ soap.RoundTripper, req *vsanTypes.VsanPerfQueryPerf) (*vsanTypes.VsanPerfQueryPerfResponse, error) {
+		for _, spec := range req.QuerySpecs {
+			if strings.HasPrefix(spec.EntityRefId, "virtual-machine:") {
+				return nil, errors.New("ServerFaultCode: entity type not supported")
+			}
+		}
+		res := &vsanTypes.VsanPerfQueryPerfResponse{}
+		for _, spec := range req.QuerySpecs {
+			res.Returnval = append(res.Returnval, vsanTypes.VsanPerfEntityMetricCSV{
+				EntityRefId: spec.EntityRefId + "fake-uuid",
+				SampleInfo:  "2024-01-01 00:00:00",
+				Value: []vsanTypes.VsanPerfMetricSeriesCSV{
+					{MetricId: vsanTypes.VsanPerfMetricId{Label: "iopsRead"}, Values: "1"},
+				},
+			})
+		}
+		return res, nil
+	}
+	defer func() { vsanPerfQueryPerf = orig }()
+
+	client := &vim25.Client{}
+	cluster := object.NewClusterComputeResource(client, objectRef{name: "test-cluster"}.ref)
+	acc := &testutil.Accumulator{}
+	tags := map[string]string{"clustername": "test-cluster"}
+
+	if err := collector.getAllVsanMetrics(context.Background(), nil, cluster, nil, tags, acc); err != nil {
+		t.Fatalf("getAllVsanMetrics: %s", err)
+	}
+
+	if len(acc.Errors) == 0 {
+		t.Error("expected an error recorded for the failed vm entity group, got none")
+	}
+	if len(acc.Metrics) == 0 {
+		t.Error("expected the core entity group's metrics to still be collected, got none")
+	} else {
+		found := false
+		for _, m := range acc.Metrics {
+			if m.Measurement == vsanMetricsName {
+				if _, ok := m.Fields["cluster-domclient_iopsRead"]; ok {
+					found = true
+				}
+			}
+		}
+		if !found {
+			t.Error("expected a cluster-domclient_iopsRead field from the successful core group")
+		}
+	}
+
+	if cp := collector.checkpoint(cluster.Reference().Value, time.Now()); !cp.Before(time.Now().Add(-4 * time.Minute)) {
+		t.Errorf("checkpoint advanced past a window with a failed entity group: %v", cp)
+	}
+}
+
+// TestGetAllVsanMetricsDoesNotReRequestLastSample is a regression test for
+// the checkpoint boundary being inclusive: it verifies that once a sample
+// has been collected, the next poll's query window starts strictly after
+// that sample's timestamp, so the vSAN Performance Manager's inclusive
+// startTime doesn't cause the same sample to be re-requested (and
+// re-emitted) forever.
+func TestGetAllVsanMetricsDoesNotReRequestLastSample(t *testing.T) {
+	collector, err := NewVsanCollector(VsanCollectorConfig{})
+	if err != nil {
+		t.Fatalf("NewVsanCollector: %s", err)
+	}
+
+	const lastSample = "2024-01-01 00:05:00"
+	orig := vsanPerfQueryPerf
+	vsanPerfQueryPerf = func(_ context.Context, _ soap.RoundTripper, req *vsanTypes.VsanPerfQueryPerf) (*vsanTypes.VsanPerfQueryPerfResponse, error) {
+		res := &vsanTypes.VsanPerfQueryPerfResponse{}
+		for _, spec := range req.QuerySpecs {
+			res.Returnval = append(res.Returnval, vsanTypes.VsanPerfEntityMetricCSV{
+				EntityRefId: spec.EntityRefId + "fake-uuid",
+				SampleInfo:  lastSample,
+				Value: []vsanTypes.VsanPerfMetricSeriesCSV{
+					{MetricId: vsanTypes.VsanPerfMetricId{Label: "iopsRead"}, Values: "1"},
+				},
+			})
+		}
+		return res, nil
+	}
+	defer func() { vsanPerfQueryPerf = orig }()
+
+	client := &vim25.Client{}
+	cluster := object.NewClusterComputeResource(client, objectRef{name: "test-cluster"}.ref)
+	acc := &testutil.Accumulator{}
+	tags := map[string]string{"clustername": "test-cluster"}
+	clusterMoid := cluster.Reference().Value
+
+	if err := collector.getAllVsanMetrics(context.Background(), nil, cluster, nil, tags, acc); err != nil {
+		t.Fatalf("getAllVsanMetrics (first poll): %s", err)
+	}
+
+	lastSampleTs, err := time.Parse("2006-01-02 15:04:05", lastSample)
+	if err != nil {
+		t.Fatalf("parsing lastSample: %s", err)
+	}
+
+	nextStart := collector.checkpoint(clusterMoid, time.Now())
+	if !nextStart.After(lastSampleTs) {
+		t.Errorf("next poll's checkpoint %v does not start strictly after the last collected sample %v - the vSAN Performance Manager's inclusive startTime would re-request it", nextStart, lastSampleTs)
+	}
+}
+
+func TestFormatAndSendVsanMetricRollupTypeSelection(t *testing.T) {
+	entity := vsanTypes.VsanPerfEntityMetricCSV{
+		EntityRefId: "cluster-domclient:fake-uuid",
+		SampleInfo:  "2024-01-01 00:05:00",
+		Value: []vsanTypes.VsanPerfMetricSeriesCSV{
+			{MetricId: vsanTypes.VsanPerfMetricId{Label: "iopsRead", RollupType: "average"}, Values: "1"},
+			{MetricId: vsanTypes.VsanPerfMetricId{Label: "iopsRead", RollupType: "maximum"}, Values: "2"},
+			{MetricId: vsanTypes.VsanPerfMetricId{Label: "throughput"}, Values: "3"},
+		},
+	}
+
+	tests := []struct {
+		name       string
+		rollupType string
+		wantFields map[string]interface{}
+	}{
+		{
+			name:       "no rollup type configured keeps every rollup",
+			rollupType: "",
+			wantFields: map[string]interface{}{
+				"cluster-domclient_iopsRead":   float64(2),
+				"cluster-domclient_throughput": float64(3),
+			},
+		},
+		{
+			name:       "rollup type configured keeps the matching rollup and counters without one",
+			rollupType: "average",
+			wantFields: map[string]interface{}{
+				"cluster-domclient_iopsRead":   float64(1),
+				"cluster-domclient_throughput": float64(3),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			collector, err := NewVsanCollector(VsanCollectorConfig{RollupType: tt.rollupType})
+			if err != nil {
+				t.Fatalf("NewVsanCollector: %s", err)
+			}
+			acc := &testutil.Accumulator{}
+			collector.formatAndSendVsanMetric(entity, map[string]string{"clustername": "test-cluster"}, acc, nil)
+
+			got := map[string]interface{}{}
+			for _, m := range acc.Metrics {
+				for k, v := range m.Fields {
+					got[k] = v
+				}
+			}
+			if !reflect.DeepEqual(got, tt.wantFields) {
+				t.Errorf("fields = %v, want %v", got, tt.wantFields)
+			}
+		})
+	}
+}